@@ -0,0 +1,47 @@
+package sandbox
+
+import (
+	"runtime"
+	"sync"
+)
+
+// garbagePathMap tracks namespace paths whose Sandbox has been garbage
+// collected without Destroy having been called, so that GC can reap them.
+var (
+	gpmLock        sync.Mutex
+	garbagePathMap = make(map[string]bool)
+)
+
+func registerFinalizer(n *networkNamespace) {
+	runtime.SetFinalizer(n, func(n *networkNamespace) {
+		gpmLock.Lock()
+		garbagePathMap[n.path] = true
+		gpmLock.Unlock()
+	})
+}
+
+func unmarkForGC(path string) {
+	gpmLock.Lock()
+	delete(garbagePathMap, path)
+	gpmLock.Unlock()
+}
+
+// GC synchronously reaps any namespace paths whose Sandbox has already been
+// garbage collected but whose finalizer-driven cleanup hasn't run yet. It is
+// meant to be called during daemon shutdown and from tests, where waiting
+// for the Go runtime to run finalizers on its own schedule isn't acceptable.
+func GC() {
+	gpmLock.Lock()
+	paths := make([]string, 0, len(garbagePathMap))
+	for path := range garbagePathMap {
+		paths = append(paths, path)
+	}
+	gpmLock.Unlock()
+
+	for _, path := range paths {
+		removeNetworkNamespace(path)
+		gpmLock.Lock()
+		delete(garbagePathMap, path)
+		gpmLock.Unlock()
+	}
+}
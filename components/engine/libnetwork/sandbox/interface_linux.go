@@ -0,0 +1,222 @@
+package sandbox
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+// nwIface represents the settings and identity of a network device moved
+// into a sandbox's network namespace.
+type nwIface struct {
+	srcName     string
+	dstName     string
+	address     *net.IPNet
+	addressIPv6 *net.IPNet
+	routes      []*net.IPNet
+	sysctls     map[string]string
+	mac         net.HardwareAddr
+	bridge      bool
+	ns          *networkNamespace
+}
+
+func (i *nwIface) SrcName() string {
+	return i.srcName
+}
+
+func (i *nwIface) DstName() string {
+	return i.dstName
+}
+
+func (i *nwIface) Address() *net.IPNet {
+	return i.address
+}
+
+func (i *nwIface) AddressIPv6() *net.IPNet {
+	return i.addressIPv6
+}
+
+func (i *nwIface) Routes() []*net.IPNet {
+	return i.routes
+}
+
+func (i *nwIface) MacAddress() net.HardwareAddr {
+	return i.mac
+}
+
+func (i *nwIface) Bridge() bool {
+	return i.bridge
+}
+
+// Remove moves the interface back out of the sandbox's namespace, restoring
+// its original name.
+func (i *nwIface) Remove() error {
+	return nsInvoke(i.ns.path, func(nsFD, callerFD int) error {
+		iface, err := netlink.LinkByName(i.dstName)
+		if err != nil {
+			return fmt.Errorf("failed to find interface %s in sandbox %s: %v", i.dstName, i.ns.path, err)
+		}
+
+		if err := netlink.LinkSetName(iface, i.srcName); err != nil {
+			return fmt.Errorf("failed to rename interface %s to %s: %v", i.dstName, i.srcName, err)
+		}
+
+		if err := netlink.LinkSetNsFd(iface, callerFD); err != nil {
+			return fmt.Errorf("failed to move interface %s out of sandbox: %v", i.srcName, err)
+		}
+
+		i.ns.mu.Lock()
+		for idx, e := range i.ns.iFaces {
+			if e == i {
+				i.ns.iFaces = append(i.ns.iFaces[:idx], i.ns.iFaces[idx+1:]...)
+				break
+			}
+		}
+		i.ns.mu.Unlock()
+		return nil
+	})
+}
+
+// IfaceOption is a function option type to set interface options.
+type IfaceOption func(i *nwIface)
+
+func (n *networkNamespace) Address(addr *net.IPNet) IfaceOption {
+	return func(i *nwIface) {
+		i.address = addr
+	}
+}
+
+func (n *networkNamespace) AddressIPv6(addr *net.IPNet) IfaceOption {
+	return func(i *nwIface) {
+		i.addressIPv6 = addr
+	}
+}
+
+func (n *networkNamespace) Routes(routes []*net.IPNet) IfaceOption {
+	return func(i *nwIface) {
+		i.routes = routes
+	}
+}
+
+func (n *networkNamespace) Sysctl(sysctls map[string]string) IfaceOption {
+	return func(i *nwIface) {
+		i.sysctls = sysctls
+	}
+}
+
+func (n *networkNamespace) MacAddress(mac net.HardwareAddr) IfaceOption {
+	return func(i *nwIface) {
+		i.mac = mac
+	}
+}
+
+func (n *networkNamespace) Bridge(isBridge bool) IfaceOption {
+	return func(i *nwIface) {
+		i.bridge = isBridge
+	}
+}
+
+// AddInterface moves an existing host interface identified by SrcName into
+// the sandbox, renaming it to an auto-disambiguated name derived from
+// DstPrefix and applying the given options.
+func (n *networkNamespace) AddInterface(srcName, dstPrefix string, options ...IfaceOption) error {
+	i := &nwIface{srcName: srcName, ns: n}
+	for _, opt := range options {
+		opt(i)
+	}
+
+	n.mu.Lock()
+	index := n.nextIfIndex[dstPrefix]
+	n.nextIfIndex[dstPrefix]++
+	n.mu.Unlock()
+	i.dstName = fmt.Sprintf("%s%d", dstPrefix, index)
+
+	if i.bridge {
+		// The overlay driver uses this to create its internal br0 directly
+		// inside the sandbox, so there is no host-side link to move.
+		if err := nsInvoke(n.path, func(nsFD, callerFD int) error {
+			link := &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: i.dstName, HardwareAddr: i.mac}}
+			return netlink.LinkAdd(link)
+		}); err != nil {
+			return fmt.Errorf("failed to create bridge %q in sandbox %q: %v", i.dstName, n.path, err)
+		}
+	} else {
+		iface, err := netlink.LinkByName(srcName)
+		if err != nil {
+			return fmt.Errorf("failed to get link by name %q: %v", srcName, err)
+		}
+
+		if i.mac != nil {
+			if err := netlink.LinkSetHardwareAddr(iface, i.mac); err != nil {
+				return fmt.Errorf("failed to set MAC address %q on %q: %v", i.mac, srcName, err)
+			}
+		}
+
+		targetNs, err := nsHandle(n.path)
+		if err != nil {
+			return fmt.Errorf("failed to get network namespace %q: %v", n.path, err)
+		}
+		defer targetNs.Close()
+
+		if err := netlink.LinkSetNsFd(iface, int(targetNs)); err != nil {
+			return fmt.Errorf("failed to move interface %q into sandbox %q: %v", srcName, n.path, err)
+		}
+	}
+
+	if err := nsInvoke(n.path, func(nsFD, callerFD int) error {
+		linkName := srcName
+		if i.bridge {
+			linkName = i.dstName
+		}
+
+		link, err := netlink.LinkByName(linkName)
+		if err != nil {
+			return err
+		}
+
+		if !i.bridge {
+			if err := netlink.LinkSetName(link, i.dstName); err != nil {
+				return err
+			}
+		}
+
+		if i.address != nil {
+			if err := netlink.AddrAdd(link, &netlink.Addr{IPNet: i.address}); err != nil {
+				return err
+			}
+		}
+
+		if i.addressIPv6 != nil {
+			if err := netlink.AddrAdd(link, &netlink.Addr{IPNet: i.addressIPv6}); err != nil {
+				return err
+			}
+		}
+
+		for _, route := range i.routes {
+			if err := netlink.RouteAdd(&netlink.Route{LinkIndex: link.Attrs().Index, Dst: route}); err != nil {
+				return err
+			}
+		}
+
+		if len(i.sysctls) > 0 {
+			resolved := make(map[string]string, len(i.sysctls))
+			for key, value := range i.sysctls {
+				resolved[strings.Replace(key, "<if>", i.dstName, -1)] = value
+			}
+			if err := applySysctls(resolved); err != nil {
+				return err
+			}
+		}
+
+		return netlink.LinkSetUp(link)
+	}); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.iFaces = append(n.iFaces, i)
+	n.mu.Unlock()
+	return nil
+}
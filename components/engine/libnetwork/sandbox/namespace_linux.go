@@ -0,0 +1,256 @@
+package sandbox
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+	"sync"
+
+	"github.com/docker/libnetwork/types"
+	"github.com/vishvananda/netlink"
+)
+
+// networkNamespace defines the structure for a linux network namespace
+// backed sandbox. It keeps the bookkeeping the controller and drivers need
+// in order to answer Info() queries without re-reading the kernel state.
+type networkNamespace struct {
+	path         string
+	iFaces       []*nwIface
+	gw           net.IP
+	gwv6         net.IP
+	staticRoutes []*types.StaticRoute
+	neighbors    []*neigh
+	sysctls      map[string]string
+	nextIfIndex  map[string]int
+	sandboxType  SandboxType
+	mu           sync.Mutex
+}
+
+// SandboxOption is used to pass options to NewSandbox to influence the
+// sandbox's behavior at creation time.
+type SandboxOption func(sb *networkNamespace)
+
+// OptionIngress marks the sandbox being created as the ingress sandbox.
+func OptionIngress() SandboxOption {
+	return func(sb *networkNamespace) {
+		sb.sandboxType = SandboxTypeIngress
+	}
+}
+
+// OptionLoadBalancer marks the sandbox being created as a dedicated
+// swarm-mode service load-balancer sandbox.
+func OptionLoadBalancer() SandboxOption {
+	return func(sb *networkNamespace) {
+		sb.sandboxType = SandboxTypeLoadBalancer
+	}
+}
+
+// NewSandbox provides a new Sandbox instance created in an os specific way
+// provided a key which uniquely identifies the sandbox. When isRestore is
+// true, the namespace at key is expected to already exist (e.g. because the
+// daemon restarted with the container still running) and is adopted as-is;
+// the caller is expected to follow up with Restore to repopulate the
+// in-memory Info from it.
+func NewSandbox(key string, osCreate, isRestore bool, options ...SandboxOption) (Sandbox, error) {
+	sb := &networkNamespace{path: key, nextIfIndex: make(map[string]int)}
+	for _, opt := range options {
+		opt(sb)
+	}
+
+	if !isRestore {
+		if err := createNetworkNamespace(key, osCreate); err != nil {
+			return nil, err
+		}
+		// A restored sandbox is adopting a namespace it did not create;
+		// only mark namespaces this process owns for GC, so a restored
+		// Sandbox being dropped without an explicit Destroy doesn't tear
+		// down a still-running container's namespace out from under it.
+		registerFinalizer(sb)
+	}
+
+	return sb, nil
+}
+
+func (n *networkNamespace) Key() string {
+	return n.path
+}
+
+func (n *networkNamespace) InterfaceOptions() IfaceOptionSetter {
+	return n
+}
+
+func (n *networkNamespace) Info() Info {
+	return n
+}
+
+func (n *networkNamespace) Interfaces() []Interface {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	ifaces := make([]Interface, len(n.iFaces))
+	for i, iface := range n.iFaces {
+		ifaces[i] = iface
+	}
+	return ifaces
+}
+
+func (n *networkNamespace) Gateway() net.IP {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.gw
+}
+
+func (n *networkNamespace) GatewayIPv6() net.IP {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.gwv6
+}
+
+func (n *networkNamespace) StaticRoutes() []*types.StaticRoute {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.staticRoutes
+}
+
+func (n *networkNamespace) Type() SandboxType {
+	return n.sandboxType
+}
+
+func (n *networkNamespace) SetGateway(gw net.IP) error {
+	if err := n.programGateway(gw, true); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.gw = gw
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *networkNamespace) UnsetGateway() error {
+	n.mu.Lock()
+	gw := n.gw
+	n.mu.Unlock()
+
+	if gw == nil {
+		return nil
+	}
+
+	if err := n.programGateway(gw, false); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.gw = nil
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *networkNamespace) SetGatewayIPv6(gw net.IP) error {
+	if err := n.programGateway(gw, true); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.gwv6 = gw
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *networkNamespace) UnsetGatewayIPv6() error {
+	n.mu.Lock()
+	gw := n.gwv6
+	n.mu.Unlock()
+
+	if gw == nil {
+		return nil
+	}
+
+	if err := n.programGateway(gw, false); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.gwv6 = nil
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *networkNamespace) programGateway(gw net.IP, add bool) error {
+	return nsInvoke(n.path, func(nsFD, callerFD int) error {
+		gwRoute := &netlink.Route{Gw: gw}
+		if add {
+			return netlink.RouteAdd(gwRoute)
+		}
+		return netlink.RouteDel(gwRoute)
+	})
+}
+
+func (n *networkNamespace) AddStaticRoute(r *types.StaticRoute) error {
+	if err := nsInvoke(n.path, func(nsFD, callerFD int) error {
+		return programRoute(r)
+	}); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.staticRoutes = append(n.staticRoutes, r)
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *networkNamespace) RemoveStaticRoute(r *types.StaticRoute) error {
+	if err := nsInvoke(n.path, func(nsFD, callerFD int) error {
+		return removeRoute(r)
+	}); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	for i, e := range n.staticRoutes {
+		if routeEqual(e, r) {
+			n.staticRoutes = append(n.staticRoutes[:i], n.staticRoutes[i+1:]...)
+			break
+		}
+	}
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *networkNamespace) Destroy() error {
+	runtime.SetFinalizer(n, nil)
+	unmarkForGC(n.path)
+	return removeNetworkNamespace(n.path)
+}
+
+func routeEqual(a, b *types.StaticRoute) bool {
+	if a.Destination.String() != b.Destination.String() {
+		return false
+	}
+	if a.RouteType != b.RouteType {
+		return false
+	}
+	return a.NextHop.Equal(b.NextHop)
+}
+
+func programRoute(r *types.StaticRoute) error {
+	route := &netlink.Route{Dst: r.Destination}
+	if r.NextHop != nil {
+		route.Gw = r.NextHop
+	}
+	if err := netlink.RouteAdd(route); err != nil {
+		return fmt.Errorf("failed to add route %s: %v", r.Destination, err)
+	}
+	return nil
+}
+
+func removeRoute(r *types.StaticRoute) error {
+	route := &netlink.Route{Dst: r.Destination}
+	if r.NextHop != nil {
+		route.Gw = r.NextHop
+	}
+	if err := netlink.RouteDel(route); err != nil {
+		return fmt.Errorf("failed to remove route %s: %v", r.Destination, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// +build !linux
+
+package sandbox
+
+import "fmt"
+
+// networkNamespace is an unimplemented placeholder on platforms that don't
+// support namespace sandboxing, kept only so SandboxOption has a concrete
+// type to close over.
+type networkNamespace struct{}
+
+// SandboxOption is used to pass options to NewSandbox to influence the
+// sandbox's behavior at creation time.
+type SandboxOption func(sb *networkNamespace)
+
+// OptionIngress marks the sandbox being created as the ingress sandbox.
+func OptionIngress() SandboxOption {
+	return func(sb *networkNamespace) {}
+}
+
+// OptionLoadBalancer marks the sandbox being created as a dedicated
+// swarm-mode service load-balancer sandbox.
+func OptionLoadBalancer() SandboxOption {
+	return func(sb *networkNamespace) {}
+}
+
+// neigh is an unimplemented placeholder on platforms that don't support
+// namespace sandboxing, kept only so NeighOption has a concrete type to
+// close over.
+type neigh struct{}
+
+// NeighOption is a function option type to set neighbor entry options.
+type NeighOption func(nh *neigh)
+
+// nwIface is an unimplemented placeholder on platforms that don't support
+// namespace sandboxing, kept only so IfaceOption has a concrete type to
+// close over.
+type nwIface struct{}
+
+// IfaceOption is a function option type to set interface options.
+type IfaceOption func(i *nwIface)
+
+// NewSandbox always returns an error on this platform, which does not
+// support namespace sandboxing; no Sandbox is ever created.
+func NewSandbox(key string, osCreate, isRestore bool, options ...SandboxOption) (Sandbox, error) {
+	return nil, fmt.Errorf("sandboxing is not supported on this platform")
+}
@@ -0,0 +1,184 @@
+package sandbox
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+)
+
+// neigh represents a single neighbor table entry programmed into a
+// sandbox's namespace, used by the overlay driver to push static ARP and
+// VXLAN FDB entries so that remote container MACs and VTEPs are resolved
+// without relying on broadcast/multicast discovery.
+type neigh struct {
+	dstIP    net.IP
+	dstMac   net.HardwareAddr
+	linkName string
+	family   int
+	state    int
+	flags    int
+	ns       *networkNamespace
+}
+
+func (nh *neigh) DstIP() net.IP {
+	return nh.dstIP
+}
+
+func (nh *neigh) DstMac() net.HardwareAddr {
+	return nh.dstMac
+}
+
+func (nh *neigh) LinkName() string {
+	return nh.linkName
+}
+
+func (nh *neigh) Remove() error {
+	return nh.ns.DeleteNeighbor(nh.dstIP, nh.dstMac)
+}
+
+// NeighOption is a function option type to set neighbor entry options.
+type NeighOption func(nh *neigh)
+
+// NeighborFamily sets the address family (netlink.FAMILY_V4 or
+// netlink.FAMILY_V6) of the neighbor entry. If not set, it is derived from
+// dstIP.
+func NeighborFamily(family int) NeighOption {
+	return func(nh *neigh) {
+		nh.family = family
+	}
+}
+
+// NeighborPermanent marks the neighbor entry as NUD_PERMANENT, i.e. not
+// subject to the kernel's normal ARP/NDP aging and re-resolution. This is
+// the default state for entries added via AddNeighbor.
+func NeighborPermanent() NeighOption {
+	return func(nh *neigh) {
+		nh.state = netlink.NUD_PERMANENT
+	}
+}
+
+// NeighborLearned marks the neighbor entry as NUD_REACHABLE rather than
+// NUD_PERMANENT, i.e. a normal, dynamically-aged entry as if the kernel had
+// resolved it itself instead of one pinned by the driver.
+func NeighborLearned() NeighOption {
+	return func(nh *neigh) {
+		nh.state = netlink.NUD_REACHABLE
+	}
+}
+
+// NeighborSelf sets the NTF_SELF flag, used when the entry also needs to be
+// programmed into the bridge FDB (as opposed to only the neighbor table).
+func NeighborSelf() NeighOption {
+	return func(nh *neigh) {
+		nh.flags |= netlink.NTF_SELF
+	}
+}
+
+// NeighborMaster sets the NTF_MASTER flag, used for VXLAN FDB entries that
+// identify the VTEP a remote MAC is reachable through.
+func NeighborMaster() NeighOption {
+	return func(nh *neigh) {
+		nh.flags |= netlink.NTF_MASTER
+	}
+}
+
+// AddNeighbor adds a static neighbor (ARP/NDP, or VXLAN FDB when NTF_SELF or
+// NTF_MASTER is set) entry inside the sandbox's namespace.
+func (n *networkNamespace) AddNeighbor(dstIP net.IP, dstMac net.HardwareAddr, linkName string, options ...NeighOption) error {
+	nh := &neigh{
+		dstIP:    dstIP,
+		dstMac:   dstMac,
+		linkName: linkName,
+		state:    netlink.NUD_PERMANENT,
+		ns:       n,
+	}
+	for _, opt := range options {
+		opt(nh)
+	}
+	if nh.family == 0 {
+		if dstIP.To4() == nil {
+			nh.family = netlink.FAMILY_V6
+		} else {
+			nh.family = netlink.FAMILY_V4
+		}
+	}
+
+	if err := nsInvoke(n.path, func(nsFD, callerFD int) error {
+		link, err := netlink.LinkByName(linkName)
+		if err != nil {
+			return fmt.Errorf("could not find link %q to add neighbor entry: %v", linkName, err)
+		}
+
+		return netlink.NeighSet(&netlink.Neigh{
+			LinkIndex:    link.Attrs().Index,
+			Family:       nh.family,
+			State:        nh.state,
+			Flags:        nh.flags,
+			IP:           dstIP,
+			HardwareAddr: dstMac,
+		})
+	}); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	n.neighbors = append(n.neighbors, nh)
+	n.mu.Unlock()
+	return nil
+}
+
+// DeleteNeighbor removes a previously added neighbor entry from the
+// sandbox's namespace.
+func (n *networkNamespace) DeleteNeighbor(dstIP net.IP, dstMac net.HardwareAddr) error {
+	n.mu.Lock()
+	var found *neigh
+	for _, e := range n.neighbors {
+		if e.dstIP.Equal(dstIP) && e.dstMac.String() == dstMac.String() {
+			found = e
+			break
+		}
+	}
+	n.mu.Unlock()
+
+	if found == nil {
+		return fmt.Errorf("neighbor entry %s/%s not found", dstIP, dstMac)
+	}
+
+	if err := nsInvoke(n.path, func(nsFD, callerFD int) error {
+		link, err := netlink.LinkByName(found.linkName)
+		if err != nil {
+			return fmt.Errorf("could not find link %q to delete neighbor entry: %v", found.linkName, err)
+		}
+
+		return netlink.NeighDel(&netlink.Neigh{
+			LinkIndex:    link.Attrs().Index,
+			Family:       found.family,
+			IP:           dstIP,
+			HardwareAddr: dstMac,
+		})
+	}); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	for i, e := range n.neighbors {
+		if e == found {
+			n.neighbors = append(n.neighbors[:i], n.neighbors[i+1:]...)
+			break
+		}
+	}
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *networkNamespace) Neighbors() []Neighbor {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	neighbors := make([]Neighbor, len(n.neighbors))
+	for i, nh := range n.neighbors {
+		neighbors[i] = nh
+	}
+	return neighbors
+}
@@ -0,0 +1,97 @@
+package sandbox
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// createNetworkNamespace creates (or, if osCreate is false, merely records)
+// a bind-mounted network namespace file at path.
+func createNetworkNamespace(path string, osCreate bool) error {
+	if err := createNamespaceFile(path); err != nil {
+		return err
+	}
+
+	if !osCreate {
+		return nil
+	}
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origns, err := netns.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get current network namespace: %v", err)
+	}
+	defer origns.Close()
+	defer netns.Set(origns)
+
+	if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+		return fmt.Errorf("failed to create new network namespace: %v", err)
+	}
+
+	return unix.Mount(fmt.Sprintf("/proc/self/task/%d/ns/net", unix.Gettid()), path, "none", unix.MS_BIND, "")
+}
+
+func createNamespaceFile(path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to create namespace file %s: %v", path, err)
+	}
+	return f.Close()
+}
+
+func removeNetworkNamespace(path string) error {
+	if err := unix.Unmount(path, unix.MNT_DETACH); err != nil && err != unix.EINVAL {
+		return fmt.Errorf("failed to unmount namespace %s: %v", path, err)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove namespace file %s: %v", path, err)
+	}
+	return nil
+}
+
+// nsHandle opens the namespace bind-mounted at path and returns a handle to
+// it, for use with netlink.LinkSetNsFd. The caller is responsible for
+// closing the returned handle once it is done using its file descriptor.
+func nsHandle(path string) (netns.NsHandle, error) {
+	return netns.GetFromPath(path)
+}
+
+// nsInvoke runs fn with the calling goroutine's thread switched into the
+// namespace bind-mounted at path, restoring the original namespace
+// afterwards. fn is additionally given callerFD, a handle to the namespace
+// the calling thread was in *before* the switch (e.g. the host root
+// namespace), for operations like moving a link back out of the sandbox
+// that need to reference that namespace from inside the target one. fn must
+// not close callerFD; nsInvoke closes it once fn returns.
+func nsInvoke(path string, fn func(nsFD, callerFD int) error) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origns, err := netns.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get current network namespace: %v", err)
+	}
+	defer origns.Close()
+	defer netns.Set(origns)
+
+	targetns, err := netns.GetFromPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to get network namespace %q: %v", path, err)
+	}
+	defer targetns.Close()
+
+	if err := netns.Set(targetns); err != nil {
+		return fmt.Errorf("failed to enter network namespace %q: %v", path, err)
+	}
+
+	return fn(int(targetns), int(origns))
+}
@@ -0,0 +1,29 @@
+package sandbox
+
+import "path/filepath"
+
+// defaultPrefix is the directory under which sandbox namespace files are
+// bind-mounted by default.
+const defaultPrefix = "/var/run/docker/netns"
+
+// prefix is the currently configured namespace root, see SetBasePath.
+var prefix = defaultPrefix
+
+// GenerateKey deterministically derives the bind-mount path that will hold
+// a container's network namespace. It can be called before the sandbox
+// itself is created, e.g. to pass the eventual key into a driver's
+// CreateEndpoint so the driver can program endpoint info ahead of time.
+func GenerateKey(containerID string) string {
+	maxLen := 12
+	if len(containerID) < maxLen {
+		maxLen = len(containerID)
+	}
+	return filepath.Join(prefix, containerID[:maxLen])
+}
+
+// SetBasePath sets the base path where the sandbox namespace files are
+// created. Used by rootless daemons and chroot/snap-confined installs where
+// the default /var/run/docker/netns is not writable.
+func SetBasePath(path string) {
+	prefix = path
+}
@@ -0,0 +1,34 @@
+package sandbox
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateKeyDefaultBasePath(t *testing.T) {
+	key := GenerateKey("containerid")
+	expected := filepath.Join(defaultPrefix, "containerid")
+	if key != expected {
+		t.Fatalf("expected key %q, got %q", expected, key)
+	}
+}
+
+func TestSetBasePath(t *testing.T) {
+	defer SetBasePath(defaultPrefix)
+
+	SetBasePath("/tmp/test-netns-root")
+	key := GenerateKey("containerid")
+	expected := filepath.Join("/tmp/test-netns-root", "containerid")
+	if key != expected {
+		t.Fatalf("expected key %q, got %q", expected, key)
+	}
+}
+
+func TestGenerateKeyTruncatesLongContainerID(t *testing.T) {
+	id := "averylongcontaineridthatshouldbetruncated"
+	key := GenerateKey(id)
+	expected := filepath.Join(defaultPrefix, id[:12])
+	if key != expected {
+		t.Fatalf("expected key %q, got %q", expected, key)
+	}
+}
@@ -0,0 +1,98 @@
+package sandbox
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/docker/libnetwork/types"
+	"github.com/vishvananda/netlink"
+)
+
+// Restore rebuilds the sandbox's in-memory Info from a namespace that
+// already exists on disk, rather than programming fresh interfaces, routes,
+// a gateway, neighbors and sysctls as AddInterface/AddStaticRoute/
+// SetGateway/AddNeighbor/ApplyOSLSysctls would. It is a no-op on kernel
+// state: each entry in ifaces must name an interface (looked up by its
+// DstName) that is already present in the namespace, and Restore only
+// attaches the driver's bookkeeping -- addresses, routes, MAC, sysctls
+// carried by the given IfaceOptions, plus neighbors and sysctls -- to it.
+//
+// Restore verifies every interface before adopting any of them, so a
+// missing link leaves the sandbox's Info exactly as it was before the call.
+func (n *networkNamespace) Restore(ifaces map[Iface][]IfaceOption, routes []*types.StaticRoute, gw net.IP, gw6 net.IP, neighbors []NeighborRestore, sysctls map[string]string) error {
+	restored := make([]*nwIface, 0, len(ifaces))
+
+	for iface, options := range ifaces {
+		if err := nsInvoke(n.path, func(nsFD, callerFD int) error {
+			_, err := netlink.LinkByName(iface.DstName)
+			return err
+		}); err != nil {
+			return fmt.Errorf("failed to restore interface %s (%s) in sandbox %s: %v", iface.DstName, iface.SrcName, n.path, err)
+		}
+
+		i := &nwIface{srcName: iface.SrcName, dstName: iface.DstName, ns: n}
+		for _, opt := range options {
+			opt(i)
+		}
+		restored = append(restored, i)
+	}
+
+	restoredNeighbors := make([]*neigh, 0, len(neighbors))
+	for _, nr := range neighbors {
+		family := netlink.FAMILY_V4
+		if nr.DstIP.To4() == nil {
+			family = netlink.FAMILY_V6
+		}
+		restoredNeighbors = append(restoredNeighbors, &neigh{
+			dstIP:    nr.DstIP,
+			dstMac:   nr.DstMac,
+			linkName: nr.LinkName,
+			family:   family,
+			state:    netlink.NUD_PERMANENT,
+			ns:       n,
+		})
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.iFaces = append(n.iFaces, restored...)
+	for _, i := range restored {
+		bumpNextIfIndex(n.nextIfIndex, i.dstName)
+	}
+	n.gw = gw
+	n.gwv6 = gw6
+	n.staticRoutes = append(n.staticRoutes, routes...)
+	n.neighbors = append(n.neighbors, restoredNeighbors...)
+
+	if len(sysctls) > 0 {
+		if n.sysctls == nil {
+			n.sysctls = make(map[string]string, len(sysctls))
+		}
+		for key, value := range sysctls {
+			n.sysctls[key] = value
+		}
+	}
+
+	return nil
+}
+
+// bumpNextIfIndex ensures that a later AddInterface call deriving a DstName
+// from the same prefix as dstName won't pick a suffix that collides with an
+// interface that was just restored.
+func bumpNextIfIndex(nextIfIndex map[string]int, dstName string) {
+	prefix := strings.TrimRight(dstName, "0123456789")
+	suffix := dstName[len(prefix):]
+	if suffix == "" {
+		return
+	}
+
+	var index int
+	if _, err := fmt.Sscanf(suffix, "%d", &index); err != nil {
+		return
+	}
+	if index >= nextIfIndex[prefix] {
+		nextIfIndex[prefix] = index + 1
+	}
+}
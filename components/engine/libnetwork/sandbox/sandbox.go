@@ -37,16 +37,71 @@ type Sandbox interface {
 	// Remove a static route from the sandbox.
 	RemoveStaticRoute(*types.StaticRoute) error
 
+	// Add a neighbor entry to the sandbox.
+	AddNeighbor(dstIP net.IP, dstMac net.HardwareAddr, linkName string, options ...NeighOption) error
+
+	// Delete a neighbor entry from the sandbox.
+	DeleteNeighbor(dstIP net.IP, dstMac net.HardwareAddr) error
+
+	// ApplyOSLSysctls applies the given /proc/sys/net/... kernel knobs inside
+	// the sandbox's network namespace. Keys use the usual sysctl dotted
+	// notation, e.g. "net.ipv6.conf.eth0.accept_dad".
+	ApplyOSLSysctls(sysctls map[string]string) error
+
 	// Returns an interface with methods to set interface options.
 	InterfaceOptions() IfaceOptionSetter
 
 	// Returns an interface with methods to get sandbox state.
 	Info() Info
 
+	// Restore adopts the network namespace's existing state (links, addresses,
+	// routes, neighbors, sysctls) into the sandbox's in-memory Info instead of
+	// programming fresh ones, for use when libnetwork restarts with
+	// containers that are already running. It is a no-op on kernel state but
+	// rebuilds the in-memory bookkeeping so that subsequent Sandbox calls
+	// (AddInterface, AddStaticRoute, ...) observe the pre-restart state.
+	Restore(ifaces map[Iface][]IfaceOption, routes []*types.StaticRoute, gw net.IP, gw6 net.IP, neighbors []NeighborRestore, sysctls map[string]string) error
+
 	// Destroy the sandbox
 	Destroy() error
 }
 
+// Iface identifies, by its namespace DstName, an interface that already
+// exists inside a namespace being restored. SrcName is the interface's
+// original name, which Interface.Remove renames the link back to when it
+// moves it out of the namespace; no host-side link carries it meanwhile.
+type Iface struct {
+	SrcName string
+	DstName string
+}
+
+// NeighborRestore identifies a neighbor entry that is already programmed in
+// a namespace being restored, so that Restore can re-attach it to the
+// sandbox's in-memory bookkeeping without reprogramming the kernel.
+type NeighborRestore struct {
+	DstIP    net.IP
+	DstMac   net.HardwareAddr
+	LinkName string
+}
+
+// SandboxType distinguishes the role a sandbox plays so that the controller
+// and drivers can apply different lifecycle and programming rules to it.
+type SandboxType int
+
+const (
+	// SandboxTypeContainer is an ordinary per-container sandbox.
+	SandboxTypeContainer SandboxType = iota
+
+	// SandboxTypeIngress identifies the sandbox that hosts the cluster
+	// routing-mesh load-balancer VIPs and their IPVS rules.
+	SandboxTypeIngress
+
+	// SandboxTypeLoadBalancer identifies a sandbox dedicated to a single
+	// swarm-mode service load balancer. Unlike a container sandbox, it is
+	// kept alive as long as at least one backend endpoint is attached.
+	SandboxTypeLoadBalancer
+)
+
 // IfaceOptionSetter interface defines the option setter methods for interface options.
 type IfaceOptionSetter interface {
 	// Address returns an option setter to set IPv4 address.
@@ -57,6 +112,22 @@ type IfaceOptionSetter interface {
 
 	// Address returns an option setter to set interface routes.
 	Routes([]*net.IPNet) IfaceOption
+
+	// Sysctl returns an option setter to apply per-interface kernel knobs
+	// once the interface has been moved into the sandbox. Keys may contain
+	// the placeholder "<if>", which is substituted with the interface's
+	// DstName, e.g. "net.ipv6.conf.<if>.accept_dad".
+	Sysctl(sysctls map[string]string) IfaceOption
+
+	// MacAddress returns an option setter to set the interface's MAC
+	// address, programmed via netlink before the link is moved into the
+	// sandbox.
+	MacAddress(net.HardwareAddr) IfaceOption
+
+	// Bridge returns an option setter that, when set to true, causes
+	// AddInterface to create the link as a Linux bridge inside the
+	// sandbox instead of moving one in from the host namespace.
+	Bridge(bool) IfaceOption
 }
 
 // Info represents all possible information that
@@ -79,6 +150,16 @@ type Info interface {
 	// connected routes are stored on the particular interface they refer to.)
 	StaticRoutes() []*types.StaticRoute
 
+	// Neighbor entries previously added with the AddNeighbor method.
+	Neighbors() []Neighbor
+
+	// Sysctls previously applied with ApplyOSLSysctls, so they can be
+	// reapplied if the namespace is re-populated after a restore.
+	Sysctls() map[string]string
+
+	// Type returns the SandboxType this sandbox was created with.
+	Type() SandboxType
+
 	// TODO: Add ip tables etc.
 }
 
@@ -109,4 +190,27 @@ type Interface interface {
 	// Remove an interface from the sandbox by renaming to original name
 	// and moving it out of the sandbox.
 	Remove() error
+
+	// MacAddress returns the MAC address assigned to the interface, if any.
+	MacAddress() net.HardwareAddr
+
+	// Bridge returns true if the interface was created as a Linux bridge
+	// inside the sandbox rather than moved in from the host namespace.
+	Bridge() bool
+}
+
+// Neighbor represents a single L2/L3 neighbor (ARP or NDP) table entry
+// programmed into the sandbox via AddNeighbor.
+type Neighbor interface {
+	// IP address of the remote peer.
+	DstIP() net.IP
+
+	// Hardware address of the remote peer.
+	DstMac() net.HardwareAddr
+
+	// Name of the link the entry is attached to, as passed to AddNeighbor.
+	LinkName() string
+
+	// Remove deletes the neighbor entry from the sandbox.
+	Remove() error
 }
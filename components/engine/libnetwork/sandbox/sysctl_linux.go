@@ -0,0 +1,79 @@
+package sandbox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"strings"
+
+	"github.com/vishvananda/netns"
+)
+
+// sysctlPath turns a dotted sysctl key such as "net.ipv6.conf.eth0.accept_dad"
+// into its /proc/sys file path.
+func sysctlPath(key string) string {
+	return "/proc/sys/" + strings.Replace(key, ".", "/", -1)
+}
+
+// applySysctls writes the given map of sysctl keys to values under
+// /proc/sys. The caller is expected to already be running inside the target
+// network namespace.
+func applySysctls(sysctls map[string]string) error {
+	for key, value := range sysctls {
+		path := sysctlPath(key)
+		if err := ioutil.WriteFile(path, []byte(value), 0644); err != nil {
+			return fmt.Errorf("failed to set sysctl %s=%s: %v", key, value, err)
+		}
+	}
+	return nil
+}
+
+// ApplyOSLSysctls applies the given /proc/sys/net/... kernel knobs inside
+// the sandbox's network namespace, binding the calling goroutine's OS thread
+// into the namespace for the duration of the call.
+func (n *networkNamespace) ApplyOSLSysctls(sysctls map[string]string) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	origns, err := netns.Get()
+	if err != nil {
+		return fmt.Errorf("failed to get current network namespace: %v", err)
+	}
+	defer origns.Close()
+	defer netns.Set(origns)
+
+	targetns, err := netns.GetFromPath(n.path)
+	if err != nil {
+		return fmt.Errorf("failed to get network namespace %q: %v", n.path, err)
+	}
+	defer targetns.Close()
+
+	if err := netns.Set(targetns); err != nil {
+		return fmt.Errorf("failed to enter network namespace %q: %v", n.path, err)
+	}
+
+	if err := applySysctls(sysctls); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	if n.sysctls == nil {
+		n.sysctls = make(map[string]string)
+	}
+	for key, value := range sysctls {
+		n.sysctls[key] = value
+	}
+	n.mu.Unlock()
+	return nil
+}
+
+func (n *networkNamespace) Sysctls() map[string]string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	sysctls := make(map[string]string, len(n.sysctls))
+	for k, v := range n.sysctls {
+		sysctls[k] = v
+	}
+	return sysctls
+}